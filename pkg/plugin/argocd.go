@@ -0,0 +1,68 @@
+package plugin
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+)
+
+var argoApplicationGVK = schema.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "Application"}
+
+func init() {
+	RegisterInjector(argoApplicationGVK, includeArgoManagedResources)
+}
+
+// includeArgoManagedResources fetches the live objects listed under
+// status.resources of an Argo CD Application, so the Application template
+// can show the actual state of what it manages rather than just the
+// summary Argo CD already keeps on the Application itself.
+func includeArgoManagedResources(obj runtime.Object, restConfig *rest.Config, out map[string]interface{}) error {
+	unstructuredObj, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil
+	}
+	resources, found, err := unstructured.NestedSlice(unstructuredObj.Object, "status", "resources")
+	if err != nil || !found {
+		return nil
+	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil
+	}
+	mapper, err := cachedRESTMapperForConfig(restConfig)
+	if err != nil {
+		return nil
+	}
+
+	var managed []interface{}
+	for _, r := range resources {
+		resMap, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		group, _, _ := unstructured.NestedString(resMap, "group")
+		version, _, _ := unstructured.NestedString(resMap, "version")
+		kind, _, _ := unstructured.NestedString(resMap, "kind")
+		namespace, _, _ := unstructured.NestedString(resMap, "namespace")
+		name, _, _ := unstructured.NestedString(resMap, "name")
+
+		mapping, err := mapper.RESTMapping(schema.GroupKind{Group: group, Kind: kind}, version)
+		if err != nil {
+			// Likely a CRD not yet discovered, skip rather than fail the whole render.
+			continue
+		}
+		resourceInterface := dynamicClient.Resource(mapping.Resource).Namespace(namespace)
+		live, err := resourceInterface.Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			continue
+		}
+		managed = append(managed, live.Object)
+	}
+	out["argoManagedResources"] = managed
+	return nil
+}
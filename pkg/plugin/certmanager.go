@@ -0,0 +1,79 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+)
+
+var certManagerCertificateGVK = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}
+
+func init() {
+	RegisterInjector(certManagerCertificateGVK, includeCertManagerOrdersAndChallenges)
+}
+
+// includeCertManagerOrdersAndChallenges pulls the cert-manager acme Orders
+// (and the Challenges owned by those Orders) for this Certificate, so the
+// Certificate template can explain why an in-progress issuance is stuck.
+func includeCertManagerOrdersAndChallenges(obj runtime.Object, restConfig *rest.Config, out map[string]interface{}) error {
+	unstructuredObj, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil
+	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return errors.WithMessage(err, "Failed building dynamic client")
+	}
+	mapper, err := cachedRESTMapperForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	namespace := unstructuredObj.GetNamespace()
+	orderGK := schema.GroupKind{Group: "acme.cert-manager.io", Kind: "Order"}
+	orders, err := listByLabel(dynamicClient, mapper, namespace, orderGK, "cert-manager.io/certificate-name", unstructuredObj.GetName())
+	if err != nil {
+		return err
+	}
+	out["certManagerOrders"] = orders
+
+	challengeGK := schema.GroupKind{Group: "acme.cert-manager.io", Kind: "Challenge"}
+	var challenges []interface{}
+	for _, order := range orders {
+		orderObj := unstructured.Unstructured{Object: order.(map[string]interface{})}
+		found, err := listByLabel(dynamicClient, mapper, namespace, challengeGK, "acme.cert-manager.io/order-name", orderObj.GetName())
+		if err != nil {
+			return err
+		}
+		challenges = append(challenges, found...)
+	}
+	out["certManagerChallenges"] = challenges
+	return nil
+}
+
+// listByLabel resolves gk to its namespaced GVR via mapper and lists the
+// matching objects in namespace, as plain unstructured maps.
+func listByLabel(dynamicClient dynamic.Interface, mapper meta.RESTMapper, namespace string, gk schema.GroupKind, labelKey, labelValue string) ([]interface{}, error) {
+	mapping, err := mapper.RESTMapping(gk)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "Failed resolving REST mapping for %s", gk)
+	}
+	list, err := dynamicClient.Resource(mapping.Resource).Namespace(namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", labelKey, labelValue),
+	})
+	if err != nil {
+		return nil, errors.WithMessagef(err, "Failed listing %s", gk)
+	}
+	out := make([]interface{}, 0, len(list.Items))
+	for _, item := range list.Items {
+		out = append(out, item.Object)
+	}
+	return out, nil
+}
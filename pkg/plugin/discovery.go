@@ -0,0 +1,48 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/discovery/cached/disk"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+)
+
+// discoveryCacheTTL mirrors kubectl's own default discovery cache lifetime.
+const discoveryCacheTTL = 10 * time.Minute
+
+var (
+	restMapperCacheMu sync.Mutex
+	restMapperCache   = map[string]*restmapper.DeferredDiscoveryRESTMapper{}
+)
+
+// cachedRESTMapperForConfig returns a disk-cached, deferred discovery REST
+// mapper for restConfig, reused across injector invocations so CRD GroupKinds
+// resolve to a GVR without re-discovering the API surface on every object.
+func cachedRESTMapperForConfig(restConfig *rest.Config) (*restmapper.DeferredDiscoveryRESTMapper, error) {
+	restMapperCacheMu.Lock()
+	defer restMapperCacheMu.Unlock()
+	if mapper, ok := restMapperCache[restConfig.Host]; ok {
+		return mapper, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed resolving home dir for discovery cache")
+	}
+	discoveryClient, err := disk.NewCachedDiscoveryClientForConfig(
+		restConfig,
+		filepath.Join(home, ".kube", "cache", "discovery"),
+		filepath.Join(home, ".kube", "http-cache"),
+		discoveryCacheTTL,
+	)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed building cached discovery client")
+	}
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(discoveryClient)
+	restMapperCache[restConfig.Host] = mapper
+	return mapper, nil
+}
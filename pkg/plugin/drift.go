@@ -0,0 +1,253 @@
+package plugin
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/cli-runtime/pkg/resource"
+	kyaml "sigs.k8s.io/yaml"
+)
+
+const lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+func init() {
+	funcMap["diffAgainstDesired"] = diffAgainstDesired
+}
+
+// DriftEntry describes one field whose live value differs from the desired
+// state reconstructed for the object.
+type DriftEntry struct {
+	Path                 string      `json:"path"`
+	Desired              interface{} `json:"desired"`
+	Actual               interface{} `json:"actual"`
+	ManagerThatSetActual string      `json:"managerThatSetActual,omitempty"`
+}
+
+// diffAgainstDesired is exposed to templates as {{ diffAgainstDesired . }}.
+func diffAgainstDesired(obj map[string]interface{}) []DriftEntry {
+	unstructuredObj := unstructured.Unstructured{Object: obj}
+	desired, _ := desiredState(&unstructuredObj)
+	if desired == nil {
+		return nil
+	}
+	return diffAgainstDesiredMap(obj, desired)
+}
+
+// diffAgainstDesiredManifestForInfo is the --show-drift variant used when the
+// query was built from -f: desired comes from the document in
+// resourceInfo.Source matching obj (a file may hold several "---"-separated
+// manifests), not from last-applied/managedFields.
+func diffAgainstDesiredManifestForInfo(obj map[string]interface{}, resourceInfo *resource.Info) ([]DriftEntry, error) {
+	if resourceInfo.Source == "" {
+		return nil, errors.New("resource has no manifest source to diff against")
+	}
+	body, err := ioutil.ReadFile(resourceInfo.Source)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "Failed reading manifest %s", resourceInfo.Source)
+	}
+	wanted := unstructured.Unstructured{Object: obj}
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(body)))
+	for {
+		chunk, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.WithMessagef(err, "Failed reading manifest %s", resourceInfo.Source)
+		}
+		var candidate map[string]interface{}
+		if err := kyaml.Unmarshal(chunk, &candidate); err != nil || candidate == nil {
+			continue
+		}
+		candidateObj := unstructured.Unstructured{Object: candidate}
+		if candidateObj.GetAPIVersion() == wanted.GetAPIVersion() &&
+			candidateObj.GetKind() == wanted.GetKind() &&
+			candidateObj.GetNamespace() == wanted.GetNamespace() &&
+			candidateObj.GetName() == wanted.GetName() {
+			return diffAgainstDesiredMap(obj, candidate), nil
+		}
+	}
+	return nil, errors.Errorf("no manifest for %s %s/%s found in %s", wanted.GetKind(), wanted.GetNamespace(), wanted.GetName(), resourceInfo.Source)
+}
+
+func diffAgainstDesiredMap(obj, desired map[string]interface{}) []DriftEntry {
+	unstructuredObj := unstructured.Unstructured{Object: obj}
+	owners := managedFieldOwners(&unstructuredObj)
+	var drift []DriftEntry
+	collectDrift("", desired, obj, owners, &drift)
+	sort.Slice(drift, func(i, j int) bool { return drift[i].Path < drift[j].Path })
+	return drift
+}
+
+// desiredState resolves the desired state for obj: the last-applied
+// annotation, falling back to the fields owned by the earliest
+// metadata.managedFields entry (the object's original applier).
+func desiredState(obj *unstructured.Unstructured) (map[string]interface{}, string) {
+	if raw, ok := obj.GetAnnotations()[lastAppliedConfigAnnotation]; ok {
+		var desired map[string]interface{}
+		if err := json.Unmarshal([]byte(raw), &desired); err == nil {
+			return desired, lastAppliedConfigAnnotation
+		}
+	}
+	if manager, paths := primaryFieldManager(obj); manager != "" {
+		desired := map[string]interface{}{}
+		for _, path := range paths {
+			value, found, _ := unstructured.NestedFieldNoCopy(obj.Object, path...)
+			if found {
+				_ = unstructured.SetNestedField(desired, value, path...)
+			}
+		}
+		return desired, "managedFields:" + manager
+	}
+	return nil, ""
+}
+
+// primaryFieldManager returns the manager and owned field paths of the
+// oldest entry in metadata.managedFields, treated as the object's original
+// applier.
+func primaryFieldManager(obj *unstructured.Unstructured) (string, [][]string) {
+	entries := sortedManagedFields(obj)
+	if len(entries) == 0 {
+		return "", nil
+	}
+	first := entries[0]
+	if first.FieldsV1 == nil {
+		return first.Manager, nil
+	}
+	return first.Manager, fieldsV1Paths(first.FieldsV1.Raw)
+}
+
+// managedFieldOwners maps a "."-joined field path to the manager that most
+// recently claimed it, so drift entries can report managerThatSetActual.
+func managedFieldOwners(obj *unstructured.Unstructured) map[string]string {
+	owners := map[string]string{}
+	for _, entry := range sortedManagedFields(obj) {
+		if entry.FieldsV1 == nil {
+			continue
+		}
+		for _, path := range fieldsV1Paths(entry.FieldsV1.Raw) {
+			owners[strings.Join(path, ".")] = entry.Manager
+		}
+	}
+	return owners
+}
+
+// sortedManagedFields returns metadata.managedFields ordered oldest first.
+func sortedManagedFields(obj *unstructured.Unstructured) []metav1.ManagedFieldsEntry {
+	entries := append([]metav1.ManagedFieldsEntry{}, obj.GetManagedFields()...)
+	sort.SliceStable(entries, func(i, j int) bool {
+		ti, tj := entries[i].Time, entries[j].Time
+		if ti == nil || tj == nil {
+			return false
+		}
+		return ti.Before(tj)
+	})
+	return entries
+}
+
+// fieldsV1Paths walks a metadata.managedFields FieldsV1 blob and returns the
+// leaf field paths it claims. List entries addressed by key/value
+// (k:/v: selectors) are skipped, this only resolves plain map fields.
+func fieldsV1Paths(raw []byte) [][]string {
+	var tree map[string]interface{}
+	if err := json.Unmarshal(raw, &tree); err != nil {
+		return nil
+	}
+	var paths [][]string
+	var walk func(prefix []string, node map[string]interface{})
+	walk = func(prefix []string, node map[string]interface{}) {
+		for key, val := range node {
+			if key == "." || !strings.HasPrefix(key, "f:") {
+				continue
+			}
+			path := append(append([]string{}, prefix...), strings.TrimPrefix(key, "f:"))
+			if sub, ok := val.(map[string]interface{}); ok && len(sub) > 0 {
+				walk(path, sub)
+			} else {
+				paths = append(paths, path)
+			}
+		}
+	}
+	walk(nil, tree)
+	return paths
+}
+
+// collectDrift recursively compares desired against actual, recording a
+// DriftEntry for every leaf present in desired that's missing from actual or
+// whose value differs.
+func collectDrift(prefix string, desired, actual map[string]interface{}, owners map[string]string, drift *[]DriftEntry) {
+	for key, desiredVal := range desired {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		actualVal, exists := actual[key]
+		if desiredSub, ok := desiredVal.(map[string]interface{}); ok {
+			actualSub, _ := actualVal.(map[string]interface{})
+			collectDrift(path, desiredSub, actualSub, owners, drift)
+			continue
+		}
+		if !exists || !reflect.DeepEqual(desiredVal, actualVal) {
+			*drift = append(*drift, DriftEntry{
+				Path:                 path,
+				Desired:              desiredVal,
+				Actual:               actualVal,
+				ManagerThatSetActual: owners[path],
+			})
+		}
+	}
+}
+
+// resourceDrift resolves the drift for resourceInfo: against its matching
+// manifest document when the query was built from -f, otherwise against
+// last-applied-configuration/managedFields.
+func (q ResourceStatusQuery) resourceDrift(resourceInfo *resource.Info) []DriftEntry {
+	out, err := runtime.DefaultUnstructuredConverter.ToUnstructured(resourceInfo.Object)
+	if err != nil {
+		return nil
+	}
+	if len(q.filenames) > 0 {
+		if drift, err := diffAgainstDesiredManifestForInfo(out, resourceInfo); err == nil {
+			return drift
+		}
+	}
+	return diffAgainstDesired(out)
+}
+
+// printDrift prints the resolved drift for resourceInfo to stdout, used by
+// --show-drift in text output mode.
+func (q ResourceStatusQuery) printDrift(resourceInfo *resource.Info) {
+	fmt.Print(q.driftText(resourceInfo))
+}
+
+// driftText renders the resolved drift for resourceInfo as text, or "" when
+// there's none, so --show-drift can be honored from contexts (e.g. the
+// watch-mode sink) that need the text rather than a direct stdout print.
+func (q ResourceStatusQuery) driftText(resourceInfo *resource.Info) string {
+	drift := q.resourceDrift(resourceInfo)
+	if len(drift) == 0 {
+		return ""
+	}
+	var buf strings.Builder
+	buf.WriteString("drift:\n")
+	for _, entry := range drift {
+		manager := entry.ManagerThatSetActual
+		if manager == "" {
+			manager = "unknown"
+		}
+		fmt.Fprintf(&buf, "  %s: desired=%v actual=%v (set by %s)\n", entry.Path, entry.Desired, entry.Actual, manager)
+	}
+	return buf.String()
+}
@@ -0,0 +1,87 @@
+package plugin
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newUnstructuredWithAnnotation(t *testing.T, lastApplied string) *unstructured.Unstructured {
+	t.Helper()
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	obj.SetAnnotations(map[string]string{lastAppliedConfigAnnotation: lastApplied})
+	return obj
+}
+
+func TestFieldsV1Paths(t *testing.T) {
+	raw := []byte(`{"f:spec":{"f:replicas":{},"f:template":{"f:spec":{"f:containers":{}}}},"f:status":{}}`)
+	paths := fieldsV1Paths(raw)
+	got := map[string]bool{}
+	for _, p := range paths {
+		got[joinPath(p)] = true
+	}
+	want := []string{"spec.replicas", "spec.template.spec.containers", "status"}
+	for _, w := range want {
+		if !got[w] {
+			t.Fatalf("expected path %q in %v", w, got)
+		}
+	}
+}
+
+func joinPath(p []string) string {
+	out := p[0]
+	for _, seg := range p[1:] {
+		out += "." + seg
+	}
+	return out
+}
+
+func TestCollectDriftDetectsChangedLeaf(t *testing.T) {
+	desired := map[string]interface{}{"spec": map[string]interface{}{"replicas": float64(3)}}
+	actual := map[string]interface{}{"spec": map[string]interface{}{"replicas": float64(5)}}
+	var drift []DriftEntry
+	collectDrift("", desired, actual, map[string]string{"spec.replicas": "hpa-controller"}, &drift)
+	if len(drift) != 1 {
+		t.Fatalf("expected 1 drift entry, got %d: %v", len(drift), drift)
+	}
+	entry := drift[0]
+	if entry.Path != "spec.replicas" || entry.Desired != float64(3) || entry.Actual != float64(5) {
+		t.Fatalf("unexpected drift entry: %+v", entry)
+	}
+	if entry.ManagerThatSetActual != "hpa-controller" {
+		t.Fatalf("expected manager hpa-controller, got %q", entry.ManagerThatSetActual)
+	}
+}
+
+func TestCollectDriftIgnoresEqualFields(t *testing.T) {
+	desired := map[string]interface{}{"spec": map[string]interface{}{"replicas": float64(3)}}
+	actual := map[string]interface{}{"spec": map[string]interface{}{"replicas": float64(3)}}
+	var drift []DriftEntry
+	collectDrift("", desired, actual, nil, &drift)
+	if len(drift) != 0 {
+		t.Fatalf("expected no drift, got %v", drift)
+	}
+}
+
+func TestCollectDriftFlagsMissingField(t *testing.T) {
+	desired := map[string]interface{}{"spec": map[string]interface{}{"replicas": float64(3)}}
+	actual := map[string]interface{}{"spec": map[string]interface{}{}}
+	var drift []DriftEntry
+	collectDrift("", desired, actual, nil, &drift)
+	if len(drift) != 1 || drift[0].Actual != nil {
+		t.Fatalf("expected missing field to be flagged with nil actual, got %v", drift)
+	}
+}
+
+func TestDesiredStateFromLastAppliedAnnotation(t *testing.T) {
+	obj := newUnstructuredWithAnnotation(t, `{"spec":{"replicas":3}}`)
+	desired, source := desiredState(obj)
+	if source != lastAppliedConfigAnnotation {
+		t.Fatalf("expected source %q, got %q", lastAppliedConfigAnnotation, source)
+	}
+	want := map[string]interface{}{"spec": map[string]interface{}{"replicas": float64(3)}}
+	if !reflect.DeepEqual(desired, want) {
+		t.Fatalf("desired = %v, want %v", desired, want)
+	}
+}
@@ -0,0 +1,49 @@
+package plugin
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+)
+
+// Injector augments the unstructured representation of obj (out) with
+// additional, kind-specific data before it's handed to the template
+// renderer, e.g. pulling related objects or computing derived status that
+// isn't available on the object itself.
+type Injector func(obj runtime.Object, restConfig *rest.Config, out map[string]interface{}) error
+
+var (
+	injectorRegistryMu sync.RWMutex
+	injectorRegistry   = map[schema.GroupVersionKind][]Injector{}
+)
+
+// RegisterInjector adds fn to the list of injectors run for gvk by
+// RenderResource. Matching is on the full GVK, so e.g. apps/v1 StatefulSet
+// and apps/v1beta2 StatefulSet can be injected differently, and CRDs can
+// register their own injectors the same way built-in kinds do.
+func RegisterInjector(gvk schema.GroupVersionKind, fn Injector) {
+	injectorRegistryMu.Lock()
+	defer injectorRegistryMu.Unlock()
+	injectorRegistry[gvk] = append(injectorRegistry[gvk], fn)
+}
+
+func injectorsFor(gvk schema.GroupVersionKind) []Injector {
+	injectorRegistryMu.RLock()
+	defer injectorRegistryMu.RUnlock()
+	return injectorRegistry[gvk]
+}
+
+func init() {
+	RegisterInjector(schema.GroupVersionKind{Version: "v1", Kind: "Node"}, includePodDetailsOnNode)
+	RegisterInjector(schema.GroupVersionKind{Version: "v1", Kind: "Node"}, includeNodeStatsSummary)
+	// kubectl get --raw /api/v1/nodes/minikube/proxy/stats/summary --> .pods[] | select podRef | containers[] | select name
+	RegisterInjector(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, includePodMetrics)
+	RegisterInjector(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "StatefulSet"}, includeStatefulSetDiff)
+	// Registered for every Ingress GVK the old bare-Kind lookup used to match,
+	// so upgrading from an older server API version doesn't silently drop this.
+	RegisterInjector(schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"}, includeIngressServices)
+	RegisterInjector(schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1beta1", Kind: "Ingress"}, includeIngressServices)
+	RegisterInjector(schema.GroupVersionKind{Group: "extensions", Version: "v1beta1", Kind: "Ingress"}, includeIngressServices)
+}
@@ -0,0 +1,36 @@
+package plugin
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+)
+
+func TestRegisterInjectorIsKeyedByFullGVK(t *testing.T) {
+	gvkV1 := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}
+	gvkV1beta1 := schema.GroupVersionKind{Group: "example.com", Version: "v1beta1", Kind: "Widget"}
+	noop := func(obj runtime.Object, restConfig *rest.Config, out map[string]interface{}) error { return nil }
+
+	RegisterInjector(gvkV1, noop)
+
+	if len(injectorsFor(gvkV1)) != 1 {
+		t.Fatalf("expected 1 injector for %v, got %d", gvkV1, len(injectorsFor(gvkV1)))
+	}
+	if len(injectorsFor(gvkV1beta1)) != 0 {
+		t.Fatalf("expected registering for %v not to affect %v", gvkV1, gvkV1beta1)
+	}
+}
+
+func TestInjectorsForLegacyIngressGVKs(t *testing.T) {
+	for _, gvk := range []schema.GroupVersionKind{
+		{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"},
+		{Group: "networking.k8s.io", Version: "v1beta1", Kind: "Ingress"},
+		{Group: "extensions", Version: "v1beta1", Kind: "Ingress"},
+	} {
+		if len(injectorsFor(gvk)) == 0 {
+			t.Fatalf("expected at least one injector registered for %v", gvk)
+		}
+	}
+}
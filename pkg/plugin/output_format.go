@@ -0,0 +1,136 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/resource"
+	kyaml "sigs.k8s.io/yaml"
+)
+
+// OutputFormat selects how PrintRenderedResource emits a rendered resource.
+type OutputFormat string
+
+const (
+	// OutputFormatText is the default, human-oriented template rendering.
+	OutputFormatText OutputFormat = "text"
+	// OutputFormatJSON pretty-prints one StatusSummary object per resource.
+	OutputFormatJSON OutputFormat = "json"
+	// OutputFormatYAML prints one "---"-separated StatusSummary document per resource.
+	OutputFormatYAML OutputFormat = "yaml"
+	// OutputFormatNDJSON compact-prints one StatusSummary object per line.
+	OutputFormatNDJSON OutputFormat = "ndjson"
+)
+
+// StatusSummary is the structured counterpart of the human-oriented
+// template rendering, built from the same injector outputs.
+type StatusSummary struct {
+	APIVersion   string                 `json:"apiVersion"`
+	Kind         string                 `json:"kind"`
+	Namespace    string                 `json:"namespace,omitempty"`
+	Name         string                 `json:"name"`
+	Phase        string                 `json:"phase,omitempty"`
+	Conditions   []interface{}          `json:"conditions,omitempty"`
+	RenderedText string                 `json:"renderedText"`
+	Extra        map[string]interface{} `json:"extra,omitempty"`
+	Drift        []DriftEntry           `json:"drift,omitempty"`
+}
+
+// RenderResourceSummary builds the StatusSummary for resourceInfo, including
+// drift when q.showDrift is set, so --show-drift works the same way under
+// every output format.
+func (q ResourceStatusQuery) RenderResourceSummary(resourceInfo *resource.Info) (*StatusSummary, error) {
+	out, err := q.buildRenderContext(resourceInfo.Object)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := q.parsedTemplates()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := executeTemplateForMap(tmpl, &buf, out); err != nil {
+		return nil, err
+	}
+	unstructuredObj := objMapToUnstructured(out)
+	summary := &StatusSummary{
+		APIVersion:   unstructuredObj.GetAPIVersion(),
+		Kind:         unstructuredObj.GetKind(),
+		Namespace:    unstructuredObj.GetNamespace(),
+		Name:         unstructuredObj.GetName(),
+		RenderedText: buf.String(),
+	}
+	if phase, found, _ := unstructured.NestedString(out, "status", "phase"); found {
+		summary.Phase = phase
+	}
+	if conditions, found, _ := unstructured.NestedSlice(out, "status", "conditions"); found {
+		summary.Conditions = conditions
+	}
+	summary.Extra = renderStatusSummaryExtra(tmpl, out)
+	if q.showDrift {
+		summary.Drift = q.resourceDrift(resourceInfo)
+	}
+	return summary, nil
+}
+
+// renderStatusSummaryExtra executes the "<Kind>.statusSummary" template
+// block against the already-parsed tmpl, when one exists for this kind, and
+// parses its output into Extra.
+func renderStatusSummaryExtra(tmpl *template.Template, out map[string]interface{}) map[string]interface{} {
+	kind, _ := out["kind"].(string)
+	blockName := kind + ".statusSummary"
+	if tmpl.Lookup(blockName) == nil {
+		return nil
+	}
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, blockName, out); err != nil {
+		return nil
+	}
+	var extra map[string]interface{}
+	if err := kyaml.Unmarshal(buf.Bytes(), &extra); err != nil {
+		return nil
+	}
+	return extra
+}
+
+func (q ResourceStatusQuery) printStructuredResource(resourceInfo *resource.Info) error {
+	summary, err := q.RenderResourceSummary(resourceInfo)
+	if err != nil {
+		return err
+	}
+	body, err := marshalStatusSummary(q.outputFormat, summary)
+	if err != nil {
+		return err
+	}
+	fmt.Print(body)
+	return nil
+}
+
+// marshalStatusSummary renders summary for format, trailing newline
+// included, so callers (direct printing, watch mode) can treat it uniformly.
+func marshalStatusSummary(format OutputFormat, summary *StatusSummary) (string, error) {
+	switch format {
+	case OutputFormatYAML:
+		body, err := kyaml.Marshal(summary)
+		if err != nil {
+			return "", errors.WithMessage(err, "Failed marshalling status summary to yaml")
+		}
+		return fmt.Sprintf("---\n%s", body), nil
+	case OutputFormatNDJSON:
+		body, err := json.Marshal(summary)
+		if err != nil {
+			return "", errors.WithMessage(err, "Failed marshalling status summary to json")
+		}
+		return string(body) + "\n", nil
+	default: // OutputFormatJSON
+		body, err := json.MarshalIndent(summary, "", "  ")
+		if err != nil {
+			return "", errors.WithMessage(err, "Failed marshalling status summary to json")
+		}
+		return string(body) + "\n", nil
+	}
+}
@@ -21,7 +21,6 @@ import (
 	"k8s.io/cli-runtime/pkg/resource"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
-	"k8s.io/client-go/rest"
 	"k8s.io/kubectl/pkg/cmd/get"
 	kyaml "sigs.k8s.io/yaml"
 
@@ -40,6 +39,10 @@ func NewResourceStatusQuery(
 	selector string,
 	fieldSelector string,
 	args []string,
+	templateDir string,
+	templateConfigmap string,
+	outputFormat OutputFormat,
+	showDrift bool,
 ) *ResourceStatusQuery {
 	return &ResourceStatusQuery{
 		clientGetter,
@@ -50,6 +53,10 @@ func NewResourceStatusQuery(
 		selector,
 		fieldSelector,
 		args,
+		templateDir,
+		templateConfigmap,
+		outputFormat,
+		showDrift,
 	}
 }
 
@@ -62,6 +69,19 @@ type ResourceStatusQuery struct {
 	selector         string
 	fieldSelector    string
 	args             []string
+	// templateDir, when set (--template-dir), points at a directory of
+	// .tmpl files that are layered on top of the embedded template set.
+	templateDir string
+	// templateConfigmap, when set (--template-configmap), is a
+	// "namespace/name" reference to a ConfigMap whose data entries are
+	// layered on top of the embedded template set.
+	templateConfigmap string
+	// outputFormat controls how PrintRenderedResource emits a resource,
+	// see OutputFormat.
+	outputFormat OutputFormat
+	// showDrift enables --show-drift: PrintRenderedResource additionally
+	// prints the drift between the object's desired and live state.
+	showDrift bool
 }
 
 func (q ResourceStatusQuery) resolveResourceInfos(resourceResult *resource.Result) ([]*resource.Info, error) {
@@ -256,6 +276,20 @@ func (q ResourceStatusQuery) GetIncludeOwnersFunc() func(map[string]interface{})
 	}
 }
 
+// installQueryFuncs registers the funcMap entries that need a live
+// ResourceStatusQuery (kubeGet, includeObj, getEvents, ...), so both the
+// main template renderer and anything else evaluating templates against
+// this query (e.g. --wait-for predicates) see the same functions.
+func (q ResourceStatusQuery) installQueryFuncs() {
+	funcMap["kubeGet"] = q.GetKubeGetFunc()
+	funcMap["kubeGetByLabelsMap"] = q.GetKubeGetByLabelsMapFunc()
+	funcMap["kubeGetServicesMatchingPod"] = q.GetKubeGetServicesMatchingPod()
+	funcMap["kubeGetFirst"] = q.GetKubeGetFirstFunc()
+	funcMap["includeObj"] = q.GetIncludeObjFunc()
+	funcMap["includeOwners"] = q.GetIncludeOwnersFunc()
+	funcMap["getEvents"] = q.getGetEventsFunc()
+}
+
 func (q ResourceStatusQuery) getGetEventsFunc() func(map[string]interface{}) map[string]interface{} {
 	return func(obj map[string]interface{}) map[string]interface{} {
 		unstructuredObj := unstructured.Unstructured{Object: obj}
@@ -280,41 +314,66 @@ func (q ResourceStatusQuery) getResourceQueryResults(namespace string, args []st
 }
 
 func (q ResourceStatusQuery) PrintRenderedResource(resourceInfo *resource.Info) error {
+	if q.outputFormat != OutputFormatText {
+		return q.printStructuredResource(resourceInfo)
+	}
 	renderOutput, err := q.RenderResource(resourceInfo.Object)
 	// Add a newline at the beginning of every template for readability
 	// Add a newline at the end of every template, as they don't end with a newline
 	fmt.Printf("\n%s\n", renderOutput)
+	if q.showDrift {
+		q.printDrift(resourceInfo)
+	}
 	return err
 }
 
 func (q ResourceStatusQuery) RenderResource(obj runtime.Object) (string, error) {
+	out, err := q.buildRenderContext(obj)
+	if err != nil {
+		return "", err
+	}
+	return q.renderFromContext(out)
+}
+
+// buildRenderContext converts obj to its unstructured form and runs every
+// injector registered for its GVK over it, the shared first half of both
+// RenderResource and RenderResourceSummary.
+func (q ResourceStatusQuery) buildRenderContext(obj runtime.Object) (map[string]interface{}, error) {
 	out, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
 	if err != nil {
-		return "", errors.WithMessage(err, "Failed getting unstructured object")
+		return nil, errors.WithMessage(err, "Failed getting unstructured object")
 	}
 	restConfig, err := q.clientGetter.ToRESTConfig()
 	if err != nil {
-		return "", errors.WithMessage(err, "Failed getting rest config")
-	}
-	kindInjectFuncMap := map[string][]func(obj runtime.Object, restConfig *rest.Config, out map[string]interface{}) error{
-		"Node":        {includePodDetailsOnNode, includeNodeStatsSummary},
-		"Pod":         {includePodMetrics}, // kubectl get --raw /api/v1/nodes/minikube/proxy/stats/summary --> .pods[] | select podRef | containers[] | select name
-		"StatefulSet": {includeStatefulSetDiff},
-		"Ingress":     {includeIngressServices},
-	}
-	kind := obj.GetObjectKind().GroupVersionKind().Kind
-	functions := kindInjectFuncMap[kind]
-	for _, f := range functions {
-		err = f(obj, restConfig, out)
-		if err != nil {
-			return "", err
+		return nil, errors.WithMessage(err, "Failed getting rest config")
+	}
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	for _, injector := range injectorsFor(gvk) {
+		if err := injector(obj, restConfig, out); err != nil {
+			return nil, err
 		}
 	}
+	return out, nil
+}
 
+func (q ResourceStatusQuery) renderFromContext(out map[string]interface{}) (string, error) {
+	tmpl, err := q.parsedTemplates()
+	if err != nil {
+		return "", err
+	}
 	var output bytes.Buffer
-	err = renderTemplateForMap(&output, out, &q)
-	renderOutput := output.String()
-	return renderOutput, err
+	err = executeTemplateForMap(tmpl, &output, out)
+	return output.String(), err
+}
+
+// parsedTemplates installs q's funcMap entries and returns the parsed,
+// overlay-merged template set for q, so callers needing more than one
+// template lookup (e.g. RenderResourceSummary) can reuse a single parse
+// instead of paying for getParsedTemplates (and any --template-configmap
+// fetch it implies) more than once per rendered object.
+func (q ResourceStatusQuery) parsedTemplates() (*template.Template, error) {
+	q.installQueryFuncs()
+	return getParsedTemplates(&q)
 }
 
 func RenderFile(manifestFilename string) (string, error) {
@@ -333,21 +392,20 @@ func RenderFile(manifestFilename string) (string, error) {
 }
 
 func renderTemplateForMap(wr io.Writer, v map[string]interface{}, queries ...*ResourceStatusQuery) error {
+	var query *ResourceStatusQuery
 	if len(queries) > 0 {
 		// If a ResourceStatusQuery is passed than use it, if not than its likely a test run with a local file.
-		query := queries[0]
-		funcMap["kubeGet"] = query.GetKubeGetFunc()
-		funcMap["kubeGetByLabelsMap"] = query.GetKubeGetByLabelsMapFunc()
-		funcMap["kubeGetServicesMatchingPod"] = query.GetKubeGetServicesMatchingPod()
-		funcMap["kubeGetFirst"] = query.GetKubeGetFirstFunc()
-		funcMap["includeObj"] = query.GetIncludeObjFunc()
-		funcMap["includeOwners"] = query.GetIncludeOwnersFunc()
-		funcMap["getEvents"] = query.getGetEventsFunc()
-	}
-	tmpl, err := getParsedTemplates()
+		query = queries[0]
+		query.installQueryFuncs()
+	}
+	tmpl, err := getParsedTemplates(query)
 	if err != nil {
 		return err
 	}
+	return executeTemplateForMap(tmpl, wr, v)
+}
+
+func executeTemplateForMap(tmpl *template.Template, wr io.Writer, v map[string]interface{}) error {
 	objKind := v["kind"].(string)
 	kindTemplateName := findTemplateName(tmpl, objKind)
 	return tmpl.ExecuteTemplate(wr, kindTemplateName, v)
@@ -364,7 +422,11 @@ func findTemplateName(tmpl *template.Template, kind string) string {
 	return kindTemplateName
 }
 
-func getParsedTemplates() (*template.Template, error) {
+// getParsedTemplates parses the templates embedded in the binary and, when
+// query carries overlay sources (--template-dir, --template-configmap, or a
+// user config dir), layers user-supplied templates on top so they take
+// precedence over the embedded ones in findTemplateName.
+func getParsedTemplates(query *ResourceStatusQuery) (*template.Template, error) {
 	templateText, err := getTemplate()
 	if err != nil {
 		return nil, err
@@ -379,6 +441,11 @@ func getParsedTemplates() (*template.Template, error) {
 	}
 	funcMap["include"] = include
 	tmpl.Funcs(funcMap)
+	if query != nil {
+		if err := query.loadTemplateOverlays(tmpl); err != nil {
+			return nil, err
+		}
+	}
 	return tmpl, nil
 }
 
@@ -0,0 +1,43 @@
+package plugin
+
+import (
+	"context"
+	"time"
+)
+
+// Poller polls a step function with exponential backoff, as used by
+// --wait-for. Watch mode does not poll; it is driven by informers.
+type Poller struct {
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+}
+
+// Run calls step until it returns done=true or an error, or ctx is done.
+func (p Poller) Run(ctx context.Context, step func() (done bool, err error)) error {
+	interval := p.InitialInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	maxInterval := p.MaxInterval
+	if maxInterval <= 0 {
+		maxInterval = 15 * time.Second
+	}
+	for {
+		done, err := step()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+		interval *= 2
+		if interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+}
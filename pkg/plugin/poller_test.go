@@ -0,0 +1,68 @@
+package plugin
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPollerRunStopsWhenDone(t *testing.T) {
+	calls := 0
+	poller := Poller{InitialInterval: time.Millisecond, MaxInterval: time.Millisecond}
+	err := poller.Run(context.Background(), func() (bool, error) {
+		calls++
+		return calls == 3, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestPollerRunPropagatesStepError(t *testing.T) {
+	sentinel := context.Canceled
+	poller := Poller{InitialInterval: time.Millisecond}
+	err := poller.Run(context.Background(), func() (bool, error) {
+		return false, sentinel
+	})
+	if err != sentinel {
+		t.Fatalf("expected sentinel error, got %v", err)
+	}
+}
+
+func TestPollerRunReturnsCtxErrOnTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	poller := Poller{InitialInterval: time.Millisecond, MaxInterval: time.Millisecond}
+	err := poller.Run(ctx, func() (bool, error) {
+		return false, nil
+	})
+	if err != context.DeadlineExceeded {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestPollerRunDoublesIntervalUpToMax(t *testing.T) {
+	poller := Poller{InitialInterval: 2 * time.Millisecond, MaxInterval: 8 * time.Millisecond}
+	var gaps []time.Duration
+	last := time.Now()
+	calls := 0
+	_ = poller.Run(context.Background(), func() (bool, error) {
+		now := time.Now()
+		if calls > 0 {
+			gaps = append(gaps, now.Sub(last))
+		}
+		last = now
+		calls++
+		return calls == 5, nil
+	})
+	// Gaps should roughly double (2ms, 4ms, 8ms) then stay capped at 8ms, allow generous slack for scheduling jitter.
+	want := []time.Duration{2 * time.Millisecond, 4 * time.Millisecond, 8 * time.Millisecond, 8 * time.Millisecond}
+	for i, w := range want {
+		if gaps[i] < w/2 {
+			t.Fatalf("gap[%d] = %v, want at least ~%v", i, gaps[i], w)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+)
+
+func TestSplitConfigmapRef(t *testing.T) {
+	namespace, name, err := splitConfigmapRef("kube-system/my-templates")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if namespace != "kube-system" || name != "my-templates" {
+		t.Fatalf("got namespace=%q name=%q", namespace, name)
+	}
+}
+
+func TestSplitConfigmapRefRejectsMissingSlash(t *testing.T) {
+	if _, _, err := splitConfigmapRef("my-templates"); err == nil {
+		t.Fatal("expected an error for a ref with no namespace")
+	}
+}
+
+func TestSplitConfigmapRefRejectsEmptyParts(t *testing.T) {
+	if _, _, err := splitConfigmapRef("/my-templates"); err == nil {
+		t.Fatal("expected an error for a ref with an empty namespace")
+	}
+	if _, _, err := splitConfigmapRef("kube-system/"); err == nil {
+		t.Fatal("expected an error for a ref with an empty name")
+	}
+}
+
+func TestAddTemplateDirNamesTemplateAfterKindNotFilename(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Certificate.tmpl"), []byte("certificate status"), 0o644); err != nil {
+		t.Fatalf("failed writing fixture: %v", err)
+	}
+	tmpl := template.New("templates.tmpl")
+	if err := addTemplateDir(tmpl, dir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tmpl.Lookup("Certificate") == nil {
+		t.Fatal("expected a flat Certificate.tmpl to be looked up by its bare kind name \"Certificate\"")
+	}
+}
@@ -0,0 +1,82 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// waitForBackoff is the schedule --wait-for polls on: start at 1s, double on
+// every miss, cap at 15s.
+var waitForBackoff = Poller{InitialInterval: time.Second, MaxInterval: 15 * time.Second}
+
+// PrintRenderedQueriedResourcesWaitFor polls the queried resources,
+// evaluating predicateExpr against each, until every object satisfies it or
+// timeout elapses. A predicate that errors (e.g. indexing into an empty
+// .status.conditions right after creation) counts as not satisfied rather
+// than being ignored, so a temporarily-erroring object can't make the wait
+// report success before the predicate ever actually held.
+func (q ResourceStatusQuery) PrintRenderedQueriedResourcesWaitFor(ctx context.Context, predicateExpr string, timeout time.Duration) []error {
+	q.installQueryFuncs()
+	predicate, err := template.New("wait-for").Funcs(funcMap).Parse(predicateExpr)
+	if err != nil {
+		return []error{errors.WithMessage(err, "Failed parsing --wait-for expression")}
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var lastErrs []error
+	var failing []*resource.Info
+	pollErr := waitForBackoff.Run(waitCtx, func() (bool, error) {
+		resourceInfos, err := q.getQueriedResources()
+		if err != nil {
+			lastErrs = []error{err}
+			return false, nil
+		}
+		lastErrs = q.PrintRenderedResourceInfos(resourceInfos)
+
+		failing = nil
+		for _, resourceInfo := range resourceInfos {
+			satisfied, err := evaluateWaitForPredicate(predicate, resourceInfo.Object)
+			if err != nil {
+				lastErrs = append(lastErrs, err)
+			}
+			if err != nil || !satisfied {
+				failing = append(failing, resourceInfo)
+			}
+		}
+		return len(failing) == 0, nil
+	})
+	if pollErr != nil {
+		if len(failing) > 0 {
+			fmt.Println("\ntimed out waiting for --wait-for, still failing:")
+			lastErrs = append(lastErrs, q.PrintRenderedResourceInfos(failing)...)
+		}
+		lastErrs = append(lastErrs, errors.WithMessage(pollErr, "Timed out waiting for --wait-for predicate"))
+	}
+	return lastErrs
+}
+
+// evaluateWaitForPredicate reports whether predicate renders to "true" for obj.
+func evaluateWaitForPredicate(predicate *template.Template, obj runtime.Object) (bool, error) {
+	if obj == nil {
+		return false, errors.New("Failed getting unstructured object: nil object")
+	}
+	out, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return false, errors.WithMessage(err, "Failed getting unstructured object")
+	}
+	var buf bytes.Buffer
+	if err := predicate.Execute(&buf, out); err != nil {
+		return false, errors.WithMessage(err, "Failed evaluating --wait-for expression")
+	}
+	return strings.TrimSpace(buf.String()) == "true", nil
+}
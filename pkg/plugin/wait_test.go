@@ -0,0 +1,56 @@
+package plugin
+
+import (
+	"testing"
+	"text/template"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func mustParsePredicate(t *testing.T, expr string) *template.Template {
+	t.Helper()
+	tmpl, err := template.New("wait-for").Parse(expr)
+	if err != nil {
+		t.Fatalf("failed parsing predicate: %v", err)
+	}
+	return tmpl
+}
+
+func TestEvaluateWaitForPredicateTrue(t *testing.T) {
+	pod := &v1.Pod{Status: v1.PodStatus{Phase: v1.PodRunning}}
+	satisfied, err := evaluateWaitForPredicate(mustParsePredicate(t, `{{ eq .status.phase "Running" }}`), pod)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !satisfied {
+		t.Fatal("expected predicate to be satisfied")
+	}
+}
+
+func TestEvaluateWaitForPredicateFalse(t *testing.T) {
+	pod := &v1.Pod{Status: v1.PodStatus{Phase: v1.PodPending}}
+	satisfied, err := evaluateWaitForPredicate(mustParsePredicate(t, `{{ eq .status.phase "Running" }}`), pod)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if satisfied {
+		t.Fatal("expected predicate not to be satisfied")
+	}
+}
+
+func TestEvaluateWaitForPredicateErrorsOnMissingIndex(t *testing.T) {
+	pod := &v1.Pod{} // no conditions
+	_, err := evaluateWaitForPredicate(mustParsePredicate(t, `{{ eq (index .status.conditions 0).status "True" }}`), pod)
+	if err == nil {
+		t.Fatal("expected an error indexing into empty conditions")
+	}
+}
+
+func TestEvaluateWaitForPredicateRejectsUnknownObject(t *testing.T) {
+	var nilObj runtime.Object
+	_, err := evaluateWaitForPredicate(mustParsePredicate(t, `{{ eq .status.phase "Running" }}`), nilObj)
+	if err == nil {
+		t.Fatal("expected an error converting a nil object")
+	}
+}
@@ -0,0 +1,279 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+)
+
+// debounceInterval coalesces multiple informer events for the same object
+// that arrive in quick succession (e.g. status subresource churn) into a
+// single re-render.
+const debounceInterval = 250 * time.Millisecond
+
+// OutputSink receives a re-rendered resource on every update.
+type OutputSink interface {
+	Write(uid types.UID, renderOutput string)
+}
+
+// appendSink is the default sink: behaves like `kubectl get -w`.
+type appendSink struct{}
+
+func (appendSink) Write(_ types.UID, renderOutput string) {
+	fmt.Printf("\n%s\n", renderOutput)
+}
+
+// ansiSink clears the screen and re-prints the full watched set on every
+// update, keyed by UID, instead of appending to a scrolling log.
+type ansiSink struct {
+	mu     sync.Mutex
+	latest map[types.UID]string
+	order  []types.UID
+}
+
+func newAnsiSink() *ansiSink {
+	return &ansiSink{latest: map[types.UID]string{}}
+}
+
+func (s *ansiSink) Write(uid types.UID, renderOutput string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.latest[uid]; !ok {
+		s.order = append(s.order, uid)
+	}
+	s.latest[uid] = renderOutput
+	// Clear screen and move cursor to top-left before re-printing everything.
+	fmt.Fprint(os.Stdout, "\x1b[2J\x1b[H")
+	for _, u := range s.order {
+		fmt.Printf("\n%s\n", s.latest[u])
+	}
+}
+
+// watchedEvent is a debounced informer event pending render: the GVR is
+// carried alongside the object since a shared debouncer serves every GVR
+// being watched.
+type watchedEvent struct {
+	gvr schema.GroupVersionResource
+	obj *unstructured.Unstructured
+}
+
+// eventDebouncer coalesces repeated events for the same object within
+// debounceInterval into a single render call.
+type eventDebouncer struct {
+	interval time.Duration
+	render   func(watchedEvent)
+
+	mu      sync.Mutex
+	timers  map[types.UID]*time.Timer
+	pending map[types.UID]watchedEvent
+}
+
+func newEventDebouncer(interval time.Duration, render func(watchedEvent)) *eventDebouncer {
+	return &eventDebouncer{
+		interval: interval,
+		render:   render,
+		timers:   map[types.UID]*time.Timer{},
+		pending:  map[types.UID]watchedEvent{},
+	}
+}
+
+func (d *eventDebouncer) enqueue(ev watchedEvent) {
+	uid := ev.obj.GetUID()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.pending[uid] = ev
+	if timer, ok := d.timers[uid]; ok {
+		timer.Stop()
+	}
+	d.timers[uid] = time.AfterFunc(d.interval, func() {
+		d.mu.Lock()
+		pending, ok := d.pending[uid]
+		delete(d.pending, uid)
+		delete(d.timers, uid)
+		d.mu.Unlock()
+		if ok {
+			d.render(pending)
+		}
+	})
+}
+
+// WatchRenderedQueriedResources keeps the terminal updated with the rendered
+// status of the currently queried resources until ctx is cancelled.
+func (q ResourceStatusQuery) WatchRenderedQueriedResources(ctx context.Context) []error {
+	return q.watchRenderedQueriedResources(ctx, appendSink{})
+}
+
+// WatchRenderedQueriedResourcesANSI is the opt-in, non-scrolling variant.
+func (q ResourceStatusQuery) WatchRenderedQueriedResourcesANSI(ctx context.Context) []error {
+	return q.watchRenderedQueriedResources(ctx, newAnsiSink())
+}
+
+func (q ResourceStatusQuery) watchRenderedQueriedResources(ctx context.Context, sink OutputSink) []error {
+	resourceInfos, err := q.getQueriedResources()
+	if err != nil {
+		return []error{err}
+	}
+	restConfig, err := q.clientGetter.ToRESTConfig()
+	if err != nil {
+		return []error{errors.WithMessage(err, "Failed getting rest config")}
+	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return []error{errors.WithMessage(err, "Failed building dynamic client")}
+	}
+
+	namespace := q.namespace
+	if q.allNamespaces {
+		namespace = metav1.NamespaceAll
+	}
+
+	// wanted restricts re-renders to exactly the objects getQueriedResources
+	// returned, since a GVR's informer otherwise covers every object of that
+	// kind in namespace, not just the ones the user asked about. infoByKey
+	// carries the matching resource.Info (Mapping, Source, ...) so a render
+	// triggered by an informer event still has everything PrintRenderedResource
+	// needs, not just the bare object the informer handed us.
+	wanted := map[watchedObjectKey]bool{}
+	namesByGVR := map[schema.GroupVersionResource]map[string]bool{}
+	infoByKey := map[watchedObjectKey]*resource.Info{}
+	for _, resourceInfo := range resourceInfos {
+		gvr := resourceInfo.Mapping.Resource
+		key := watchedObjectKey{gvr, resourceInfo.Namespace, resourceInfo.Name}
+		wanted[key] = true
+		infoByKey[key] = resourceInfo
+		if namesByGVR[gvr] == nil {
+			namesByGVR[gvr] = map[string]bool{}
+		}
+		namesByGVR[gvr][resourceInfo.Name] = true
+	}
+
+	// Renders run on a single worker goroutine: installQueryFuncs (called by
+	// every render, via RenderResource/RenderResourceSummary) mutates the
+	// shared package-level funcMap, and eventDebouncer fires each object's
+	// callback from its own time.AfterFunc goroutine, so rendering directly
+	// from there would be a concurrent map write.
+	renders := make(chan watchedEvent, 32)
+	go func() {
+		for ev := range renders {
+			key := watchedObjectKey{ev.gvr, ev.obj.GetNamespace(), ev.obj.GetName()}
+			base := infoByKey[key]
+			if base == nil {
+				continue
+			}
+			renderOutput, err := q.renderForSink(withObject(base, ev.obj))
+			if err != nil {
+				continue
+			}
+			sink.Write(ev.obj.GetUID(), renderOutput)
+		}
+	}()
+	debouncer := newEventDebouncer(debounceInterval, func(ev watchedEvent) { renders <- ev })
+
+	seenGVRs := map[schema.GroupVersionResource]bool{}
+	for _, resourceInfo := range resourceInfos {
+		gvr := resourceInfo.Mapping.Resource
+		if seenGVRs[gvr] {
+			continue
+		}
+		seenGVRs[gvr] = true
+
+		tweakListOptions := func(options *metav1.ListOptions) {
+			options.LabelSelector = q.selector
+			options.FieldSelector = q.fieldSelector
+			// A single named object of this GVR (e.g. `kubectl status -w pod myapp`)
+			// can be scoped server-side too; multiple names fall back to the
+			// client-side `wanted` check below.
+			if names := namesByGVR[gvr]; len(names) == 1 {
+				for name := range names {
+					options.FieldSelector = fieldSelectorWithName(options.FieldSelector, name)
+				}
+			}
+		}
+		factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(dynamicClient, 0, namespace, tweakListOptions)
+		informer := factory.ForResource(gvr).Informer()
+		handler := func(obj interface{}) {
+			u, ok := obj.(*unstructured.Unstructured)
+			if !ok || !wanted[watchedObjectKey{gvr, u.GetNamespace(), u.GetName()}] {
+				return
+			}
+			debouncer.enqueue(watchedEvent{gvr, u})
+		}
+		_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    handler,
+			UpdateFunc: func(_, obj interface{}) { handler(obj) },
+		})
+		if err != nil {
+			return []error{errors.WithMessage(err, "Failed registering informer event handler")}
+		}
+		factory.Start(ctx.Done())
+		factory.WaitForCacheSync(ctx.Done())
+	}
+
+	<-ctx.Done()
+	return nil
+}
+
+// renderForSink renders resourceInfo the same way PrintRenderedResource
+// would, so watch mode honors --output and --show-drift instead of always
+// falling back to the plain text template.
+func (q ResourceStatusQuery) renderForSink(resourceInfo *resource.Info) (string, error) {
+	if q.outputFormat != OutputFormatText {
+		summary, err := q.RenderResourceSummary(resourceInfo)
+		if err != nil {
+			return "", err
+		}
+		return marshalStatusSummary(q.outputFormat, summary)
+	}
+	renderOutput, err := q.RenderResource(resourceInfo.Object)
+	if err != nil {
+		return "", err
+	}
+	if q.showDrift {
+		if driftText := q.driftText(resourceInfo); driftText != "" {
+			renderOutput += "\n" + driftText
+		}
+	}
+	return renderOutput, nil
+}
+
+// withObject returns a shallow copy of base with Object (and the Name/
+// Namespace mirrored from it) replaced by obj, the freshly observed version
+// of the same resource from the informer.
+func withObject(base *resource.Info, obj *unstructured.Unstructured) *resource.Info {
+	clone := *base
+	clone.Object = obj
+	clone.Name = obj.GetName()
+	clone.Namespace = obj.GetNamespace()
+	return &clone
+}
+
+// watchedObjectKey identifies one of the objects getQueriedResources
+// originally returned, so informer events for other objects of the same GVR
+// can be ignored.
+type watchedObjectKey struct {
+	gvr       schema.GroupVersionResource
+	namespace string
+	name      string
+}
+
+// fieldSelectorWithName appends a metadata.name field selector to an
+// existing one, if any.
+func fieldSelectorWithName(existing, name string) string {
+	nameSelector := "metadata.name=" + name
+	if existing == "" {
+		return nameSelector
+	}
+	return existing + "," + nameSelector
+}
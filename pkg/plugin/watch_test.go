@@ -0,0 +1,58 @@
+package plugin
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func objWithUID(uid string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	obj.SetUID(types.UID(uid))
+	return obj
+}
+
+func TestEventDebouncerCoalescesRapidEvents(t *testing.T) {
+	var mu sync.Mutex
+	var renders int
+	debouncer := newEventDebouncer(20*time.Millisecond, func(ev watchedEvent) {
+		mu.Lock()
+		renders++
+		mu.Unlock()
+	})
+
+	obj := objWithUID("a")
+	for i := 0; i < 5; i++ {
+		debouncer.enqueue(watchedEvent{obj: obj})
+	}
+	time.Sleep(60 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if renders != 1 {
+		t.Fatalf("expected 5 rapid events to coalesce into 1 render, got %d", renders)
+	}
+}
+
+func TestEventDebouncerRendersDistinctObjectsIndependently(t *testing.T) {
+	var mu sync.Mutex
+	seen := map[string]bool{}
+	debouncer := newEventDebouncer(10*time.Millisecond, func(ev watchedEvent) {
+		mu.Lock()
+		seen[string(ev.obj.GetUID())] = true
+		mu.Unlock()
+	})
+
+	debouncer.enqueue(watchedEvent{obj: objWithUID("a")})
+	debouncer.enqueue(watchedEvent{obj: objWithUID("b")})
+	time.Sleep(40 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !seen["a"] || !seen["b"] {
+		t.Fatalf("expected both objects to be rendered, got %v", seen)
+	}
+}